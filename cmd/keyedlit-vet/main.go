@@ -0,0 +1,26 @@
+// Command keyedlit-vet is a multichecker bundling keyedlit with a curated
+// set of upstream golang.org/x/tools analysis passes, for teams that would
+// rather adopt a single go vet -vettool= binary than compose several.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+	"golang.org/x/tools/go/analysis/passes/lostcancel"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/analysis/passes/structtag"
+	"golang.org/x/tools/go/analysis/passes/unusedresult"
+
+	"github.com/mewbak/analysis/passes/keyedlit"
+)
+
+func main() {
+	multichecker.Main(
+		keyedlit.Analyzer,
+		lostcancel.Analyzer,
+		printf.Analyzer,
+		shadow.Analyzer,
+		structtag.Analyzer,
+		unusedresult.Analyzer,
+	)
+}
@@ -0,0 +1,22 @@
+// Command keyedlit runs the keyedlit analysis pass as a go vet plugin:
+//
+//	go vet -vettool=$(which keyedlit) ./...
+//
+// This is the standard distribution shape for golang.org/x/tools/go/analysis
+// passes, and lets gopls and CI pipelines that shell out to go vet pick up
+// keyedlit alongside the rest of vet's checks.
+//
+// For direct, non-vet invocation, golang.org/x/tools/go/analysis/singlechecker
+// is a drop-in replacement for unitchecker here, with the same
+// Analyzer-registration API.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/unitchecker"
+
+	"github.com/mewbak/analysis/passes/keyedlit"
+)
+
+func main() {
+	unitchecker.Main(keyedlit.Analyzer)
+}
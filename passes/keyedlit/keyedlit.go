@@ -1,9 +1,11 @@
 // Package keyedlit defines an analysis pass that checks that keyed literals'
 // fields are explicitly set.
 //
-// It currently checks that any field whose name contains 'Timeout' or
-// 'KeepAlive' is explicitly set instead of relying on default values. If the
-// 'strict' flag is set, all exported fields must be specified.
+// By default it checks that any field whose name contains 'Timeout' or
+// 'KeepAlive' and whose type is time.Duration is explicitly set instead of
+// relying on default values. If the 'strict' flag is set, all exported
+// fields must be specified. The set of mandatory fields can be replaced
+// entirely with the 'rules' flag; see below.
 //
 // This pass guards against users trusting the default timeout value of 0 which
 // usually indicates an infinite value. Timeouts and KeepAlives should never be
@@ -14,14 +16,53 @@
 // specify all exported field values. This guards against updated dependencies
 // adding new fields that merit consideration.
 //
+// The 'include' and 'exclude' flags, each repeatable, take regular
+// expressions matched against the fully-qualified type name
+// (e.g. "net/http.Client") of the literal's type. When at least one
+// 'include' pattern is given, only types matching one of them are checked;
+// 'exclude' patterns then remove types from that set. With no 'include'
+// patterns, every struct type is eligible. This lets a project enforce
+// exhaustive keyed literals for a handful of sensitive types (net/http.Client,
+// crypto/tls.Config, internal/config.*, ...) without going strict globally.
+//
+// Type resolution is based on type-checker information rather than the
+// literal's AST shape, so generic instantiations (Foo[int]{...}) and nested
+// literals with an elided type ([]Inner{{Timeout: ...}}) are checked
+// just like any other keyed literal.
+//
+// A composite literal marked with a "keyedlit:ignore" comment is never
+// reported on. A struct type declaration marked with "keyedlit:require
+// FieldA,FieldB" forces those fields to be checked even outside -strict, and
+// a struct field marked "keyedlit:optional" is exempt from -strict. These
+// give a project-local escape hatch, and let library authors annotate their
+// own exported types once.
+//
+// The 'rules' flag points at a JSON file of rules, each shaped like:
+//
+//	{"name_regexp": "(?i)timeout|deadline|keepalive", "type_regexp": "time\\.Duration"}
+//
+// A field is mandatory (outside -strict) when its name matches name_regexp,
+// its type's string form matches type_regexp, and its owning struct's
+// fully-qualified name matches applies_to_type_regexp (all default to
+// matching everything when omitted). This turns the checker from a
+// two-case special-purpose linter into a general "important field missing"
+// framework, e.g. requiring TLSConfig on any *Server, or MaxOpenConns on a
+// sql.DB wrapper. Without -rules, the built-in Timeout/KeepAlive rule above
+// applies.
+//
 // This pass believes explicit is better: code is read many more times, and
 // often with more at stake, than it is written.
 package keyedlit
 
 import (
+	"encoding/json"
 	"flag"
+	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"os"
+	"regexp"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
@@ -29,16 +70,31 @@ import (
 	"golang.org/x/tools/go/ast/inspector"
 )
 
+// Directive comments recognized on composite literals, struct type
+// declarations, and struct fields. See the package doc for their meaning.
+const (
+	directiveIgnore   = "keyedlit:ignore"
+	directiveRequire  = "keyedlit:require"
+	directiveOptional = "keyedlit:optional"
+)
+
 const Doc = `check for important, unspecified fields in keyed literals.
 
 This checker reports unset Timeout / KeepAlive fields in keyed literals. These
 are often overlooked (e.g., when preparing a net/http.Client) and lead to
 production issues due to the default value of infinity for Timeout and no
-KeepAlives.`
+KeepAlives.
+
+The -include and -exclude flags restrict which struct types, by
+fully-qualified name, are checked at all. The -rules flag replaces the
+built-in Timeout/KeepAlive rule with a custom set of mandatory-field rules.`
 
 // flags
 var (
-	strictF bool
+	strictF  bool
+	includeF regexpsFlag
+	excludeF regexpsFlag
+	rulesF   string
 )
 
 var Analyzer = &analysis.Analyzer{
@@ -50,12 +106,262 @@ var Analyzer = &analysis.Analyzer{
 	Flags: func() flag.FlagSet {
 		fs := flag.NewFlagSet("keyedlit", flag.ExitOnError)
 		fs.BoolVar(&strictF, "strict", false, "must specify all exported fields in keyed literals")
+		fs.Var(&includeF, "include", "regexp matching the fully-qualified type name of literals to check (repeatable); if unset, all types are eligible")
+		fs.Var(&excludeF, "exclude", "regexp matching the fully-qualified type name of literals to skip (repeatable)")
+		fs.StringVar(&rulesF, "rules", "", "path to a JSON file of rules overriding the built-in required-field rules")
 		return *fs
 	}(),
 }
 
+// regexpsFlag is a repeatable flag.Value collecting compiled regular
+// expressions, e.g. -include=net/http.Client -include=crypto/tls.Config.
+// Since it implements flag.Value, Analyzer.Flags.Set("include", pattern)
+// also works as a programmatic equivalent to the command-line flag.
+type regexpsFlag struct {
+	raw []string
+	res []*regexp.Regexp
+}
+
+func (f *regexpsFlag) String() string { return strings.Join(f.raw, ",") }
+
+func (f *regexpsFlag) Set(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	f.raw = append(f.raw, pattern)
+	f.res = append(f.res, re)
+	return nil
+}
+
+func (f *regexpsFlag) anyMatch(s string) bool {
+	for _, re := range f.res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeAllowed reports whether a literal of the given fully-qualified type
+// name should be checked, per the -include/-exclude flags.
+func typeAllowed(qualifiedName string) bool {
+	if len(includeF.res) > 0 && !includeF.anyMatch(qualifiedName) {
+		return false
+	}
+	return !excludeF.anyMatch(qualifiedName)
+}
+
+// qualifier prints a package's full import path, so that types.TypeString
+// produces names like "net/http.Client" rather than just "http.Client". It
+// also names generic instantiations correctly, e.g. "example.com/cache.Store[int]".
+func qualifier(pkg *types.Package) string {
+	return pkg.Path()
+}
+
+// Rule describes one "this field is mandatory" rule, as loaded from the
+// -rules JSON file. An empty pattern matches everything.
+type Rule struct {
+	NameRegexp          string `json:"name_regexp"`
+	TypeRegexp          string `json:"type_regexp"`
+	AppliesToTypeRegexp string `json:"applies_to_type_regexp"`
+}
+
+// defaultRules reproduces this pass's original, hardcoded behavior: any
+// Timeout or KeepAlive field of type time.Duration is mandatory.
+var defaultRules = []Rule{
+	{NameRegexp: "KeepAlive", TypeRegexp: "^time\\.Duration$"},
+	{NameRegexp: "Timeout", TypeRegexp: "^time\\.Duration$"},
+}
+
+// compiledRule is a Rule with its patterns compiled.
+type compiledRule struct {
+	name, typ, appliesTo *regexp.Regexp
+}
+
+func (r compiledRule) matches(fieldName, fieldType, ownerName string) bool {
+	return r.name.MatchString(fieldName) &&
+		r.typ.MatchString(fieldType) &&
+		r.appliesTo.MatchString(ownerName)
+}
+
+// loadRules loads and compiles the rules file at path (or the built-in
+// defaults, when path is empty). It's re-read on every run rather than
+// cached, since -rules is just another analyzer flag and can differ between
+// invocations of the same process (e.g. in tests).
+func loadRules(path string) ([]compiledRule, error) {
+	raw := defaultRules
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("keyedlit: reading -rules file: %w", err)
+		}
+		var fileRules []Rule
+		if err := json.Unmarshal(data, &fileRules); err != nil {
+			return nil, fmt.Errorf("keyedlit: parsing -rules file: %w", err)
+		}
+		raw = fileRules
+	}
+	compiled := make([]compiledRule, len(raw))
+	for i, r := range raw {
+		cr, err := compileRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("keyedlit: rule %d: %w", i, err)
+		}
+		compiled[i] = cr
+	}
+	return compiled, nil
+}
+
+func compileRule(r Rule) (compiledRule, error) {
+	var cr compiledRule
+	var err error
+	if cr.name, err = regexp.Compile(r.NameRegexp); err != nil {
+		return compiledRule{}, err
+	}
+	if cr.typ, err = regexp.Compile(r.TypeRegexp); err != nil {
+		return compiledRule{}, err
+	}
+	if cr.appliesTo, err = regexp.Compile(r.AppliesToTypeRegexp); err != nil {
+		return compiledRule{}, err
+	}
+	return cr, nil
+}
+
+// directives holds the //keyedlit:* directive comments found while scanning
+// a package, so that run and mustBeSpecified can consult them without
+// re-walking the AST.
+type directives struct {
+	ignoreLits     map[*ast.CompositeLit]bool
+	requiredFields map[*types.Named]map[string]bool
+	optionalFields map[*types.Named]map[string]bool
+}
+
+// collectDirectives pre-walks pass.Files for //keyedlit:* comments,
+// associating "ignore" with the composite literal it appears on (by source
+// line, since a literal is an expression rather than a declaration) and
+// "require"/"optional" with the enclosing struct type via ast.CommentMap,
+// which handles doc comments on declarations and fields correctly.
+func collectDirectives(pass *analysis.Pass) *directives {
+	d := &directives{
+		ignoreLits:     map[*ast.CompositeLit]bool{},
+		requiredFields: map[*types.Named]map[string]bool{},
+		optionalFields: map[*types.Named]map[string]bool{},
+	}
+	for _, file := range pass.Files {
+		lines := commentsByLine(pass.Fset, file)
+		ast.Inspect(file, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.CompositeLit); ok && hasIgnoreDirective(pass.Fset, lines, lit) {
+				d.ignoreLits[lit] = true
+			}
+			return true
+		})
+
+		cmap := ast.NewCommentMap(pass.Fset, file, file.Comments)
+		for _, decl := range file.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				obj, ok := pass.TypesInfo.Defs[ts.Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+				named, ok := obj.Type().(*types.Named)
+				if !ok {
+					continue
+				}
+				if args, ok := directiveArgs(cmap[gd], directiveRequire); ok {
+					d.requiredFields[named] = stringSet(args)
+				}
+				for _, field := range st.Fields.List {
+					if _, ok := directiveArgs(cmap[field], directiveOptional); ok {
+						if d.optionalFields[named] == nil {
+							d.optionalFields[named] = map[string]bool{}
+						}
+						for _, name := range field.Names {
+							d.optionalFields[named][name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return d
+}
+
+// commentsByLine indexes every comment in file by its 1-based source line.
+func commentsByLine(fset *token.FileSet, file *ast.File) map[int]string {
+	lines := map[int]string{}
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			lines[fset.Position(c.Pos()).Line] = c.Text
+		}
+	}
+	return lines
+}
+
+// hasIgnoreDirective reports whether lit has a "keyedlit:ignore" comment
+// either trailing its opening line or leading it on the line above.
+func hasIgnoreDirective(fset *token.FileSet, lines map[int]string, lit *ast.CompositeLit) bool {
+	startLine := fset.Position(lit.Pos()).Line
+	for _, line := range [...]int{startLine, startLine - 1} {
+		if text, ok := lines[line]; ok && strings.Contains(text, directiveIgnore) {
+			return true
+		}
+	}
+	return false
+}
+
+// directiveArgs scans groups for a line starting with directive and, if
+// found, returns its comma-separated arguments (possibly none).
+func directiveArgs(groups []*ast.CommentGroup, directive string) ([]string, bool) {
+	for _, cg := range groups {
+		for _, c := range cg.List {
+			text := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(c.Text, "//"), "/*"), "*/"))
+			if !strings.HasPrefix(text, directive) {
+				continue
+			}
+			rest := strings.TrimSpace(strings.TrimPrefix(text, directive))
+			if rest == "" {
+				return nil, true
+			}
+			var args []string
+			for _, a := range strings.Split(rest, ",") {
+				if a = strings.TrimSpace(a); a != "" {
+					args = append(args, a)
+				}
+			}
+			return args, true
+		}
+	}
+	return nil, false
+}
+
+func stringSet(ss []string) map[string]bool {
+	set := make(map[string]bool, len(ss))
+	for _, s := range ss {
+		set[s] = true
+	}
+	return set
+}
+
 func run(pass *analysis.Pass) (interface{}, error) {
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	dirs := collectDirectives(pass)
+	rules, err := loadRules(rulesF)
+	if err != nil {
+		return nil, err
+	}
 
 	nodeFilter := []ast.Node{
 		(*ast.CompositeLit)(nil),
@@ -66,24 +372,37 @@ func run(pass *analysis.Pass) (interface{}, error) {
 			return
 		}
 		lit := n.(*ast.CompositeLit)
-		// Get the type being created.
-		t := pass.TypesInfo.TypeOf(lit).Underlying()
+		if dirs.ignoreLits[lit] {
+			return
+		}
+		// Get the type being created. pass.TypesInfo.TypeOf consults the
+		// Types map, which go/types populates for every composite literal,
+		// including ones whose Type is nil because it was elided in a
+		// nested literal (e.g. []Inner{{Timeout: ...}}) and ones
+		// written as generic instantiations (Foo[int]{...}), so this works
+		// without needing to inspect lit.Type's AST shape at all.
+		typ := pass.TypesInfo.TypeOf(lit)
+		if typ == nil {
+			return
+		}
 		// Ignore it unless the type is that of a struct.
-		s, ok := t.(*types.Struct)
+		s, ok := typ.Underlying().(*types.Struct)
 		if !ok {
 			return
 		}
-		var typeName string
-		switch x := lit.Type.(type) {
-		case *ast.SelectorExpr:
-			typeName = x.Sel.String()
-		case *ast.Ident:
-			typeName = x.String()
-		default:
+		// Anonymous struct literals have no name to report or to match
+		// -include/-exclude against.
+		named, ok := typ.(*types.Named)
+		if !ok {
 			return
 		}
-		// Ignore unless this is a keyed composite literal.
-		isKeyedLiteral := false
+		typeName := types.TypeString(typ, types.RelativeTo(pass.Pkg))
+		if !typeAllowed(types.TypeString(typ, qualifier)) {
+			return
+		}
+		// Ignore unless this is a keyed composite literal. An empty
+		// literal (Foo{}) is vacuously keyed: it has no unkeyed elements
+		// to object to, and every field is implicitly unset.
 		for _, e := range lit.Elts {
 			_, ok := e.(*ast.KeyValueExpr)
 			if !ok {
@@ -91,16 +410,12 @@ func run(pass *analysis.Pass) (interface{}, error) {
 				// of them are.
 				return
 			}
-			isKeyedLiteral = true
-		}
-		if !isKeyedLiteral {
-			return
 		}
 		// Loop through its fields, looking for ones that contain the
 		// substrings 'Timeout' or 'KeepAlive'.
 		for ii := 0; ii < s.NumFields(); ii++ {
 			field := s.Field(ii)
-			if mustBeSpecified(field) {
+			if mustBeSpecified(field, named, dirs, rules) {
 				fieldIsSpecified := false
 				for _, el := range lit.Elts {
 					kve := el.(*ast.KeyValueExpr)
@@ -115,7 +430,11 @@ func run(pass *analysis.Pass) (interface{}, error) {
 					}
 				}
 				if !fieldIsSpecified {
-					pass.Reportf(lit.Pos(), "unspecified field %s of %s", field.Name(), typeName)
+					pass.Report(analysis.Diagnostic{
+						Pos:            lit.Pos(),
+						Message:        fmt.Sprintf("unspecified field %s of %s", field.Name(), typeName),
+						SuggestedFixes: suggestedFixes(pass, lit, field),
+					})
 				}
 			}
 		}
@@ -125,20 +444,137 @@ func run(pass *analysis.Pass) (interface{}, error) {
 	return nil, nil
 }
 
-func mustBeSpecified(field *types.Var) bool {
+// suggestedFixes builds the fix that inserts field, set to its zero value,
+// into lit immediately before the literal's closing brace.
+func suggestedFixes(pass *analysis.Pass, lit *ast.CompositeLit, field *types.Var) []analysis.SuggestedFix {
+	qf := func(p *types.Package) string {
+		if p == pass.Pkg {
+			return ""
+		}
+		return p.Name()
+	}
+	zero := zeroValue(field.Type(), qf)
+	indent := indentFor(pass, lit)
+	sep := ""
+	if needsLeadingComma(pass, lit) {
+		sep = ","
+	}
+	newText := fmt.Sprintf("%s\n%s%s: %s,", sep, indent, field.Name(), zero)
+	return []analysis.SuggestedFix{{
+		Message: fmt.Sprintf("add %s: %s", field.Name(), zero),
+		TextEdits: []analysis.TextEdit{{
+			Pos:     lit.Rbrace,
+			End:     lit.Rbrace,
+			NewText: []byte(newText),
+		}},
+	}}
+}
+
+// needsLeadingComma reports whether the text we're about to insert before
+// lit.Rbrace must start with a comma to separate it from lit's last existing
+// element. That's the case unless the author already left a trailing comma
+// after it (the usual style for multi-line literals) -- without one, the
+// inserted field would run into the prior element with nothing between them.
+func needsLeadingComma(pass *analysis.Pass, lit *ast.CompositeLit) bool {
+	if len(lit.Elts) == 0 {
+		return false
+	}
+	last := lit.Elts[len(lit.Elts)-1]
+	filename := pass.Fset.Position(last.End()).Filename
+	src, err := pass.ReadFile(filename)
+	if err != nil {
+		return true
+	}
+	start := pass.Fset.Position(last.End()).Offset
+	end := pass.Fset.Position(lit.Rbrace).Offset
+	if start < 0 || end > len(src) || start > end {
+		return true
+	}
+	between := strings.TrimLeft(string(src[start:end]), " \t\r\n")
+	return !strings.HasPrefix(between, ",")
+}
+
+// indentFor returns the leading whitespace that a newly inserted field should
+// be prefixed with so that it lines up with lit's existing elements. For an
+// empty literal, which must be expanded into multi-line form, it indents one
+// level deeper than the literal itself.
+func indentFor(pass *analysis.Pass, lit *ast.CompositeLit) string {
+	anchor := lit.Lbrace
+	if len(lit.Elts) > 0 {
+		anchor = lit.Elts[len(lit.Elts)-1].Pos()
+	}
+	filename := pass.Fset.Position(anchor).Filename
+	src, err := pass.ReadFile(filename)
+	if err != nil {
+		return "\t"
+	}
+	pos := pass.Fset.Position(anchor)
+	lineStart := pos.Offset - (pos.Column - 1)
+	if lineStart < 0 || pos.Offset > len(src) {
+		return "\t"
+	}
+	line := src[lineStart:pos.Offset]
+	indent := line[:len(line)-len(strings.TrimLeft(string(line), " \t"))]
+	if len(lit.Elts) == 0 {
+		indent = append(append([]byte{}, indent...), '\t')
+	}
+	return string(indent)
+}
+
+// zeroValue renders the zero value of t the way it would appear as the
+// right-hand side of a keyed literal element, e.g. "0", `""`, "nil", or
+// "time.Duration(0)" for named numeric types. Type names are written with qf,
+// so types from other packages carry their package name, as if imported
+// under it.
+func zeroValue(t types.Type, qf types.Qualifier) string {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false"
+		case u.Info()&types.IsString != 0:
+			return `""`
+		case u.Info()&types.IsNumeric != 0:
+			if _, named := t.(*types.Named); named {
+				return types.TypeString(t, qf) + "(0)"
+			}
+			return "0"
+		}
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Interface, *types.Signature:
+		return "nil"
+	}
+	return types.TypeString(t, qf) + "{}"
+}
+
+func mustBeSpecified(field *types.Var, owner *types.Named, dirs *directives, rules []compiledRule) bool {
+	// collectDirectives keys its maps on the generic type's declaration, but
+	// owner here may be a distinct instantiated *types.Named (e.g. Box[int]
+	// for a Box[T any] declaration). Origin() maps either back to the same
+	// declaration pointer, so directives keep working on generic types.
+	declOwner := owner
+	if owner != nil {
+		declOwner = owner.Origin()
+	}
+	// A "keyedlit:optional" field is never required, regardless of mode.
+	if dirs.optionalFields[declOwner][field.Name()] {
+		return false
+	}
+	// A "keyedlit:require"d field is always required, even outside -strict.
+	if dirs.requiredFields[declOwner][field.Name()] {
+		return true
+	}
 	if strictF {
 		// In strict mode, all exported fields must be specified.
 		return field.Exported()
 	}
-	// In non-strict mode, only KeepAlive and Timeout fields must be
-	// specified.
-	if strings.Contains(field.Name(), "KeepAlive") &&
-		field.Type().String() == "time.Duration" {
-		return true
+	var ownerName string
+	if owner != nil {
+		ownerName = types.TypeString(owner, qualifier)
 	}
-	if strings.Contains(field.Name(), "Timeout") &&
-		field.Type().String() == "time.Duration" {
-		return true
+	for _, r := range rules {
+		if r.matches(field.Name(), field.Type().String(), ownerName) {
+			return true
+		}
 	}
 	return false
 }
@@ -0,0 +1,68 @@
+package keyedlit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// resetFlags restores every analyzer flag to its zero value. Tests run in
+// one process sharing the package-level flag vars, so each subtest that
+// changes them must reset before and after.
+func resetFlags() {
+	strictF = false
+	includeF = regexpsFlag{}
+	excludeF = regexpsFlag{}
+	rulesF = ""
+}
+
+func TestDefaultRules(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), Analyzer, "a")
+}
+
+func TestStrict(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	strictF = true
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "strict")
+}
+
+func TestIncludeExclude(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	if err := includeF.Set(`filter\.(Included|ExcludedByPattern)$`); err != nil {
+		t.Fatal(err)
+	}
+	if err := excludeF.Set(`filter\.ExcludedByPattern$`); err != nil {
+		t.Fatal(err)
+	}
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "filter")
+}
+
+func TestEmbeddedAndAliasedTypes(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "embed")
+}
+
+func TestGenericsAndNestedLiterals(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "generics")
+}
+
+func TestDirectives(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "directive")
+}
+
+func TestCustomRules(t *testing.T) {
+	resetFlags()
+	t.Cleanup(resetFlags)
+	rulesF = filepath.Join(analysistest.TestData(), "src", "rules", "rules.json")
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "rules")
+}
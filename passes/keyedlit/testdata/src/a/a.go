@@ -0,0 +1,47 @@
+package a
+
+import "time"
+
+// Client mirrors the shape this pass's doc comment uses as its motivating
+// example (net/http.Client) closely enough to exercise the default
+// Timeout rule without actually importing net/http.
+type Client struct {
+	Transport int
+	Timeout   time.Duration
+}
+
+func singleLine() Client {
+	return Client{Transport: 1} // want `unspecified field Timeout of Client`
+}
+
+func multiLineNoTrailingComma() Client {
+	return Client{ // want `unspecified field Timeout of Client`
+		Transport: 1}
+}
+
+func multiLineTrailingComma() Client {
+	return Client{ // want `unspecified field Timeout of Client`
+		Transport: 1,
+	}
+}
+
+func empty() Client {
+	return Client{} // want `unspecified field Timeout of Client`
+}
+
+func unkeyed() Client {
+	// Positional literals are never reported, regardless of what's missing.
+	return Client{1, 0}
+}
+
+func fullySpecified() Client {
+	return Client{Transport: 1, Timeout: 5 * time.Second}
+}
+
+func anonymous() interface{} {
+	// Anonymous struct literals have no name to report, so they're skipped
+	// even though the field is unset.
+	return struct {
+		Timeout time.Duration
+	}{}
+}
@@ -0,0 +1,69 @@
+package directive
+
+import "time"
+
+type Client struct {
+	Timeout time.Duration
+}
+
+func ignored() Client {
+	return Client{} // keyedlit:ignore
+}
+
+func ignoredLeading() Client {
+	// keyedlit:ignore
+	return Client{}
+}
+
+func notIgnored() Client {
+	return Client{} // want `unspecified field Timeout of Client`
+}
+
+// Config has no fields that match the default Timeout/KeepAlive rule, but
+// "keyedlit:require Retries" forces Retries to be checked anyway.
+//
+// keyedlit:require Retries
+type Config struct {
+	Retries int
+	Name    string
+}
+
+func missingRequired() Config {
+	return Config{Name: "x"} // want `unspecified field Retries of Config`
+}
+
+func hasRequired() Config {
+	return Config{Retries: 3, Name: "x"}
+}
+
+// GenericConfig exercises "keyedlit:require" on a generic struct: the
+// directive is collected against the declaration's *types.Named, but a
+// literal's type (GenericConfig[int]) is a distinct instantiated
+// *types.Named, so the lookup must go through Named.Origin().
+//
+// keyedlit:require Retries
+type GenericConfig[T any] struct {
+	Value   T
+	Retries int
+}
+
+func missingRequiredGeneric() GenericConfig[int] {
+	return GenericConfig[int]{Value: 1} // want `unspecified field Retries of GenericConfig\[int\]`
+}
+
+func hasRequiredGeneric() GenericConfig[int] {
+	return GenericConfig[int]{Value: 1, Retries: 3}
+}
+
+// Strict is only relevant in -strict mode; this package runs with -strict
+// off, so strictOptionalField below exercises "keyedlit:optional" having no visible
+// effect outside -strict (it's covered for real by the strict package).
+type Strict struct {
+	// keyedlit:optional
+	Extra   int
+	Timeout time.Duration
+}
+
+func strictOptionalField() Strict {
+	return Strict{} // want `unspecified field Timeout of Strict`
+}
@@ -0,0 +1,27 @@
+package embed
+
+import "time"
+
+// Dur is a plain alias for time.Duration: field.Type().String() resolves
+// through it to "time.Duration", so the default rule still matches.
+type Dur = time.Duration
+
+type Base struct {
+	Timeout Dur
+}
+
+type Derived struct {
+	Base
+	Name string
+}
+
+func useBase() Base {
+	return Base{} // want `unspecified field Timeout of Base`
+}
+
+func useDerived() Derived {
+	// Derived's own fields are "Base" and "Name"; the promoted Timeout
+	// field from the embedded struct isn't one of Derived's fields, so it
+	// isn't checked (or reportable) at this level.
+	return Derived{Name: "x"}
+}
@@ -0,0 +1,33 @@
+package filter
+
+import "time"
+
+// Included matches the test's -include pattern and not its -exclude
+// pattern, so it's still checked.
+type Included struct {
+	Timeout time.Duration
+}
+
+// ExcludedByPattern matches both -include and -exclude, so -exclude wins
+// and it's skipped.
+type ExcludedByPattern struct {
+	Timeout time.Duration
+}
+
+// NotIncluded doesn't match the -include pattern at all, so with at least
+// one -include pattern set, it's skipped regardless of -exclude.
+type NotIncluded struct {
+	Timeout time.Duration
+}
+
+func useIncluded() Included {
+	return Included{} // want `unspecified field Timeout of Included`
+}
+
+func useExcluded() ExcludedByPattern {
+	return ExcludedByPattern{}
+}
+
+func useNotIncluded() NotIncluded {
+	return NotIncluded{}
+}
@@ -0,0 +1,23 @@
+package generics
+
+import "time"
+
+type Box[T any] struct {
+	Value   T
+	Timeout time.Duration
+}
+
+func missingOnInstantiation() Box[int] {
+	return Box[int]{Value: 1} // want `unspecified field Timeout of Box\[int\]`
+}
+
+type Inner struct {
+	Timeout time.Duration
+}
+
+func missingOnNested() []Inner {
+	// Inner's type is elided here (legal only for elements of an array,
+	// slice, or map literal); it must still be resolved from type info
+	// rather than the literal's own (nil) Type field.
+	return []Inner{{}} // want `unspecified field Timeout of Inner`
+}
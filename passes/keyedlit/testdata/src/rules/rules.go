@@ -0,0 +1,21 @@
+package rules
+
+import "time"
+
+type TLSConfig struct{}
+
+// Server is targeted by the -rules file's applies_to_type_regexp; with a
+// custom -rules file in effect, the built-in Timeout rule no longer
+// applies, but TLSConfig becomes mandatory.
+type Server struct {
+	TLSConfig TLSConfig
+	Timeout   time.Duration
+}
+
+func missingTLSConfig() Server {
+	return Server{} // want `unspecified field TLSConfig of Server`
+}
+
+func hasTLSConfig() Server {
+	return Server{TLSConfig: TLSConfig{}}
+}
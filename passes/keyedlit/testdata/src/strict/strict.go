@@ -0,0 +1,36 @@
+package strict
+
+type Config struct {
+	Name string
+	Port int
+	// keyedlit:optional
+	Extra      int
+	unexported int
+}
+
+func missingExported() Config {
+	return Config{Name: "x"} // want `unspecified field Port of Config`
+}
+
+func fullySpecified() Config {
+	return Config{Name: "x", Port: 8080, Extra: 0}
+}
+
+func optionalFieldNeverRequired() Config {
+	return Config{Name: "x", Port: 8080}
+}
+
+// GenericConfig exercises "keyedlit:optional" on a generic struct under
+// -strict: the directive is collected against the declaration's
+// *types.Named, but GenericConfig[int] is a distinct instantiated
+// *types.Named, so the lookup must go through Named.Origin().
+type GenericConfig[T any] struct {
+	Value T
+	// keyedlit:optional
+	Extra int
+}
+
+func genericOptionalFieldNeverRequired() GenericConfig[int] {
+	// Extra is keyedlit:optional, so -strict must not flag it as missing.
+	return GenericConfig[int]{Value: 1}
+}